@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// buildAwsSession resolves AWS credentials for the configured storage
+// provider, trying each source in the order a user is most likely to have
+// intentionally set it: explicit static keys (with the optional session
+// token), a named profile, then the SDK's default chain (env, shared
+// config/profile, EC2/ECS metadata). When RoleArn is set, those credentials
+// are replaced by the role: via web-identity federation if a token file is
+// also set, otherwise by assuming the role via STS with them.
+func buildAwsSession(awsConfig AwsConfig) aws.Config {
+	ctx := context.Background()
+
+	opts := []func(*config.LoadOptions) error{config.WithRegion(awsConfig.Region)}
+
+	switch {
+	case awsConfig.AccessKeyId != "" && awsConfig.SecretAccessKey != "":
+		opts = append(opts, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(awsConfig.AccessKeyId, awsConfig.SecretAccessKey, awsConfig.SessionToken),
+		))
+	case awsConfig.Profile != "":
+		opts = append(opts, config.WithSharedConfigProfile(awsConfig.Profile))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	PanicIfError(err)
+
+	if awsConfig.RoleArn != "" && awsConfig.WebIdentityTokenFile != "" {
+		stsClient := sts.NewFromConfig(cfg)
+		cfg.Credentials = aws.NewCredentialsCache(
+			stscreds.NewWebIdentityRoleProvider(stsClient, awsConfig.RoleArn, stscreds.IdentityTokenFile(awsConfig.WebIdentityTokenFile)),
+		)
+	} else if awsConfig.RoleArn != "" {
+		stsClient := sts.NewFromConfig(cfg)
+		cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(stsClient, awsConfig.RoleArn))
+	}
+
+	return cfg
+}
+
+// NewS3Client builds the S3 client the Iceberg writer/reader use for
+// STORAGE_TYPE_S3, layering the endpoint override and path-style addressing
+// that S3-compatible providers (MinIO, R2, GCS, IBM COS) need on top of the
+// credentials buildAwsSession resolves.
+func NewS3Client(awsConfig AwsConfig) *s3.Client {
+	awsCfg := buildAwsSession(awsConfig)
+
+	return s3.NewFromConfig(awsCfg, func(options *s3.Options) {
+		if awsConfig.Endpoint != "" {
+			options.BaseEndpoint = aws.String(awsConfig.Endpoint)
+		}
+		options.UsePathStyle = awsConfig.S3ForcePathStyle
+	})
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBuildAwsSessionStaticCredentials(t *testing.T) {
+	awsConfig := AwsConfig{
+		Region:          "us-east-1",
+		AccessKeyId:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+	}
+
+	cfg := buildAwsSession(awsConfig)
+
+	if cfg.Region != "us-east-1" {
+		t.Fatalf("expected region us-east-1, got %q", cfg.Region)
+	}
+
+	creds, err := cfg.Credentials.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error retrieving credentials: %v", err)
+	}
+	if creds.AccessKeyID != "AKIDEXAMPLE" || creds.SecretAccessKey != "secret" || creds.SessionToken != "token" {
+		t.Fatalf("unexpected credentials: %+v", creds)
+	}
+}
+
+func TestStorageProviderDefaults(t *testing.T) {
+	if !storageProviderDefaults[STORAGE_PROVIDER_MINIO].S3ForcePathStyle {
+		t.Fatal("expected MinIO to default to path-style addressing")
+	}
+	if storageProviderDefaults[STORAGE_PROVIDER_GCS].Endpoint == "" {
+		t.Fatal("expected GCS to default to a storage.googleapis.com endpoint")
+	}
+	if storageProviderDefaults[STORAGE_PROVIDER_R2].S3ForcePathStyle {
+		t.Fatal("expected R2 to default to virtual-hosted-style addressing")
+	}
+}
+
+func TestNewS3ClientUsesEndpointOverride(t *testing.T) {
+	client := NewS3Client(AwsConfig{
+		Region:           "us-east-1",
+		AccessKeyId:      "AKIDEXAMPLE",
+		SecretAccessKey:  "secret",
+		Endpoint:         "http://localhost:9000",
+		S3ForcePathStyle: true,
+	})
+
+	if client == nil {
+		t.Fatal("expected a non-nil S3 client")
+	}
+
+	options := client.Options()
+	if options.BaseEndpoint == nil || *options.BaseEndpoint != "http://localhost:9000" {
+		t.Fatalf("expected base endpoint http://localhost:9000, got %v", options.BaseEndpoint)
+	}
+	if !options.UsePathStyle {
+		t.Fatal("expected path-style addressing to be enabled")
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
+)
+
+// azureServiceEndpoint returns the account-level endpoint (no container
+// suffix), honoring an explicit endpoint override before falling back to the
+// default public Azure Blob Storage domain.
+func azureServiceEndpoint(azureConfig AzureConfig) string {
+	endpoint := azureConfig.Endpoint
+	if endpoint == "" {
+		endpoint = "https://" + azureConfig.AccountName + ".blob.core.windows.net"
+	}
+	return strings.TrimSuffix(endpoint, "/")
+}
+
+// BuildAzureContainerUrl returns the base URL for the configured container,
+// e.g. https://<account>.blob.core.windows.net/<container>. It honors an
+// explicit endpoint override before falling back to the default public
+// Azure Blob Storage domain, and appends the SAS token as the query string
+// when one is set.
+func BuildAzureContainerUrl(azureConfig AzureConfig) string {
+	url := azureServiceEndpoint(azureConfig) + "/" + azureConfig.Container
+	if azureConfig.SasToken != "" {
+		url += "?" + strings.TrimPrefix(azureConfig.SasToken, "?")
+	}
+
+	return url
+}
+
+// BuildAzureBlobUrl returns the full URL for a blob within the configured
+// container, joining the container URL with the given key.
+func BuildAzureBlobUrl(azureConfig AzureConfig, key string) string {
+	containerUrl := BuildAzureContainerUrl(azureConfig)
+	if azureConfig.SasToken == "" {
+		return containerUrl + "/" + strings.TrimPrefix(key, "/")
+	}
+
+	base, query, _ := strings.Cut(containerUrl, "?")
+	return base + "/" + strings.TrimPrefix(key, "/") + "?" + query
+}
+
+// NewAzureContainerClient builds the container client the Iceberg
+// writer/reader use for STORAGE_TYPE_AZURE, trying credentials in the same
+// precedence order the config flags document: an explicit connection
+// string, then a SAS token (the token is embedded in the URL, so no
+// credential object is needed), then a shared account key.
+func NewAzureContainerClient(azureConfig AzureConfig) (*container.Client, error) {
+	var serviceClient *service.Client
+	var err error
+
+	if azureConfig.ConnectionString != "" {
+		serviceClient, err = service.NewClientFromConnectionString(azureConfig.ConnectionString, nil)
+	} else if azureConfig.SasToken != "" {
+		return container.NewClientWithNoCredential(BuildAzureContainerUrl(azureConfig), nil)
+	} else {
+		var credential *azblob.SharedKeyCredential
+		credential, err = azblob.NewSharedKeyCredential(azureConfig.AccountName, azureConfig.AccountKey)
+		if err == nil {
+			serviceClient, err = service.NewClientWithSharedKeyCredential(azureServiceEndpoint(azureConfig), credential, nil)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return serviceClient.NewContainerClient(azureConfig.Container), nil
+}
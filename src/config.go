@@ -3,7 +3,9 @@ package main
 import (
 	"flag"
 	"os"
+	"runtime"
 	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -17,14 +19,44 @@ const (
 	ENV_LOG_LEVEL         = "BEMIDB_LOG_LEVEL"
 	ENV_STORAGE_TYPE      = "BEMIDB_STORAGE_TYPE"
 
-	ENV_AWS_REGION            = "AWS_REGION"
-	ENV_AWS_S3_BUCKET         = "AWS_S3_BUCKET"
-	ENV_AWS_ACCESS_KEY_ID     = "AWS_ACCESS_KEY_ID"
-	ENV_AWS_SECRET_ACCESS_KEY = "AWS_SECRET_ACCESS_KEY"
+	ENV_AWS_REGION                  = "AWS_REGION"
+	ENV_AWS_S3_BUCKET               = "AWS_S3_BUCKET"
+	ENV_AWS_ACCESS_KEY_ID           = "AWS_ACCESS_KEY_ID"
+	ENV_AWS_SECRET_ACCESS_KEY       = "AWS_SECRET_ACCESS_KEY"
+	ENV_AWS_SESSION_TOKEN           = "AWS_SESSION_TOKEN"
+	ENV_AWS_ENDPOINT                = "AWS_ENDPOINT"
+	ENV_AWS_S3_FORCE_PATH_STYLE     = "AWS_S3_FORCE_PATH_STYLE"
+	ENV_AWS_PROFILE                 = "AWS_PROFILE"
+	ENV_AWS_ROLE_ARN                = "AWS_ROLE_ARN"
+	ENV_AWS_WEB_IDENTITY_TOKEN_FILE = "AWS_WEB_IDENTITY_TOKEN_FILE"
+	ENV_STORAGE_PROVIDER            = "STORAGE_PROVIDER"
+
+	ENV_AZURE_STORAGE_ACCOUNT           = "AZURE_STORAGE_ACCOUNT"
+	ENV_AZURE_STORAGE_KEY               = "AZURE_STORAGE_KEY"
+	ENV_AZURE_STORAGE_CONTAINER         = "AZURE_STORAGE_CONTAINER"
+	ENV_AZURE_STORAGE_ENDPOINT          = "AZURE_STORAGE_ENDPOINT"
+	ENV_AZURE_STORAGE_SAS_TOKEN         = "AZURE_STORAGE_SAS_TOKEN"
+	ENV_AZURE_STORAGE_CONNECTION_STRING = "AZURE_STORAGE_CONNECTION_STRING"
 
 	ENV_PG_DATABASE_URL  = "PG_DATABASE_URL"
 	ENV_PG_SYNC_INTERVAL = "PG_SYNC_INTERVAL"
 	ENV_PG_SCHEMA_PREFIX = "PG_SCHEMA_PREFIX"
+	ENV_PG_SYNC_MODE     = "PG_SYNC_MODE"
+	ENV_PG_EXPORT_FORMAT = "PG_EXPORT_FORMAT"
+
+	ENV_SYNC_CONCURRENCY = "BEMIDB_SYNC_CONCURRENCY"
+
+	ENV_NOTIFY_WEBHOOK_URL   = "BEMIDB_NOTIFY_WEBHOOK_URL"
+	ENV_NOTIFY_SNS_TOPIC_ARN = "BEMIDB_NOTIFY_SNS_TOPIC_ARN"
+	ENV_NOTIFY_EVENTS        = "BEMIDB_NOTIFY_EVENTS"
+
+	ENV_NOTIFY_AWS_REGION            = "BEMIDB_NOTIFY_AWS_REGION"
+	ENV_NOTIFY_AWS_ACCESS_KEY_ID     = "BEMIDB_NOTIFY_AWS_ACCESS_KEY_ID"
+	ENV_NOTIFY_AWS_SECRET_ACCESS_KEY = "BEMIDB_NOTIFY_AWS_SECRET_ACCESS_KEY"
+	ENV_NOTIFY_AWS_PROFILE           = "BEMIDB_NOTIFY_AWS_PROFILE"
+	ENV_NOTIFY_AWS_ROLE_ARN          = "BEMIDB_NOTIFY_AWS_ROLE_ARN"
+
+	DEFAULT_NOTIFY_EVENTS = "start,success,failure,table_failed,table_synced"
 
 	DEFAULT_PORT              = "54321"
 	DEFAULT_DATABASE          = "bemidb"
@@ -38,19 +70,84 @@ const (
 
 	STORAGE_TYPE_LOCAL = "LOCAL"
 	STORAGE_TYPE_S3    = "S3"
+	STORAGE_TYPE_AZURE = "AZURE"
+
+	PG_SYNC_MODE_SNAPSHOT    = "snapshot"
+	PG_SYNC_MODE_INCREMENTAL = "incremental"
+	PG_SYNC_MODE_CDC         = "cdc"
+	DEFAULT_PG_SYNC_MODE     = PG_SYNC_MODE_SNAPSHOT
+
+	PG_EXPORT_FORMAT_CSV    = "csv"
+	PG_EXPORT_FORMAT_BINARY = "binary"
+	DEFAULT_PG_EXPORT_FORMAT = PG_EXPORT_FORMAT_BINARY
+
+	STORAGE_PROVIDER_AWS    = "aws"
+	STORAGE_PROVIDER_MINIO  = "minio"
+	STORAGE_PROVIDER_R2     = "r2"
+	STORAGE_PROVIDER_GCS    = "gcs"
+	STORAGE_PROVIDER_IBMCOS = "ibmcos"
+	STORAGE_PROVIDER_OTHER  = "other"
+	DEFAULT_STORAGE_PROVIDER = STORAGE_PROVIDER_AWS
 )
 
+var PG_SYNC_MODES = []string{PG_SYNC_MODE_SNAPSHOT, PG_SYNC_MODE_INCREMENTAL, PG_SYNC_MODE_CDC}
+
+var PG_EXPORT_FORMATS = []string{PG_EXPORT_FORMAT_CSV, PG_EXPORT_FORMAT_BINARY}
+
+var STORAGE_PROVIDERS = []string{
+	STORAGE_PROVIDER_AWS, STORAGE_PROVIDER_MINIO, STORAGE_PROVIDER_R2,
+	STORAGE_PROVIDER_GCS, STORAGE_PROVIDER_IBMCOS, STORAGE_PROVIDER_OTHER,
+}
+
+// storageProviderDefaults pre-sets the endpoint and path-style behavior that
+// each S3-compatible provider expects, so users only need to supply
+// credentials and a bucket. An explicit --aws-endpoint still wins.
+var storageProviderDefaults = map[string]struct {
+	Endpoint         string
+	S3ForcePathStyle bool
+}{
+	STORAGE_PROVIDER_MINIO:  {Endpoint: "", S3ForcePathStyle: true},
+	STORAGE_PROVIDER_R2:     {Endpoint: "", S3ForcePathStyle: false},
+	STORAGE_PROVIDER_GCS:    {Endpoint: "https://storage.googleapis.com", S3ForcePathStyle: false},
+	STORAGE_PROVIDER_IBMCOS: {Endpoint: "https://s3.us.cloud-object-storage.appdomain.cloud", S3ForcePathStyle: false},
+}
+
 type AwsConfig struct {
-	Region          string
-	S3Bucket        string
-	AccessKeyId     string
-	SecretAccessKey string
+	Region               string
+	S3Bucket             string
+	AccessKeyId          string
+	SecretAccessKey      string
+	SessionToken         string // optional
+	Endpoint             string // optional, overrides the default AWS S3 endpoint
+	S3ForcePathStyle     bool
+	Profile              string // optional, named profile from the shared AWS config/credentials files
+	RoleArn              string // optional, assumed via STS using the static/profile credentials above
+	WebIdentityTokenFile string // optional, used for OIDC federation (e.g. EKS/GitHub Actions)
+	StorageProvider      string
+}
+
+type AzureConfig struct {
+	ConnectionString string
+	AccountName      string
+	AccountKey       string
+	Container        string
+	Endpoint         string // optional, derived from AccountName when unset
+	SasToken         string // optional, used instead of AccountKey
 }
 
 type PgConfig struct {
 	DatabaseUrl  string
 	SyncInterval string // optional
 	SchemaPrefix string // optional
+	SyncMode     string
+	ExportFormat string
+}
+
+type NotifyConfig struct {
+	WebhookUrl  string // optional
+	SnsTopicArn string // optional
+	Events      []string
+	Aws         AwsConfig // credentials for publishing to SnsTopicArn, independent of --storage-type
 }
 
 type Config struct {
@@ -63,8 +160,11 @@ type Config struct {
 	LogLevel          string
 	StorageType       string
 	StoragePath       string
+	SyncConcurrency   int
 	Aws               AwsConfig
+	Azure             AzureConfig
 	Pg                PgConfig
+	Notify            NotifyConfig
 }
 
 var _config Config
@@ -116,6 +216,18 @@ func registerFlags() {
 		_config.InitSqlFilepath = DEFAULT_INIT_SQL_FILEPATH
 	}
 
+	var syncConcurrency string
+	flag.StringVar(&syncConcurrency, "sync-concurrency", os.Getenv(ENV_SYNC_CONCURRENCY), "Number of tables to sync in parallel (default: number of CPUs)")
+	if syncConcurrency == "" {
+		_config.SyncConcurrency = runtime.NumCPU()
+	} else {
+		parsedConcurrency, err := strconv.Atoi(syncConcurrency)
+		if err != nil || parsedConcurrency < 1 {
+			panic("Invalid sync concurrency " + syncConcurrency + ". Must be a positive integer")
+		}
+		_config.SyncConcurrency = parsedConcurrency
+	}
+
 	flag.StringVar(&_config.LogLevel, "log-level", os.Getenv(ENV_LOG_LEVEL), "Log level: DEBUG, INFO, ERROR (default: '"+DEFAULT_LOG_LEVEL+"')")
 	if _config.LogLevel == "" {
 		_config.LogLevel = DEFAULT_LOG_LEVEL
@@ -135,11 +247,57 @@ func registerFlags() {
 	flag.StringVar(&_config.Pg.SyncInterval, "pg-sync-interval", os.Getenv(ENV_PG_SYNC_INTERVAL), "(Optional) Interval between syncs (e.g., 1h, 30m). Valid time units are 'ns', 'us' (or 'µs'), 'ms', 's', 'm', 'h'.")
 	flag.StringVar(&_config.Pg.DatabaseUrl, "pg-database-url", os.Getenv(ENV_PG_DATABASE_URL), "PostgreSQL database URL")
 
+	flag.StringVar(&_config.Pg.SyncMode, "pg-sync-mode", os.Getenv(ENV_PG_SYNC_MODE), "PostgreSQL sync mode: snapshot, incremental, cdc (default: '"+DEFAULT_PG_SYNC_MODE+"')")
+	if _config.Pg.SyncMode == "" {
+		_config.Pg.SyncMode = DEFAULT_PG_SYNC_MODE
+	} else if !slices.Contains(PG_SYNC_MODES, _config.Pg.SyncMode) {
+		panic("Invalid PostgreSQL sync mode " + _config.Pg.SyncMode + ". Must be one of " + strings.Join(PG_SYNC_MODES, ", "))
+	}
+
+	flag.StringVar(&_config.Pg.ExportFormat, "pg-export-format", os.Getenv(ENV_PG_EXPORT_FORMAT), "PostgreSQL export format: csv, binary (default: '"+DEFAULT_PG_EXPORT_FORMAT+"')")
+	if _config.Pg.ExportFormat == "" {
+		_config.Pg.ExportFormat = DEFAULT_PG_EXPORT_FORMAT
+	} else if !slices.Contains(PG_EXPORT_FORMATS, _config.Pg.ExportFormat) {
+		panic("Invalid PostgreSQL export format " + _config.Pg.ExportFormat + ". Must be one of " + strings.Join(PG_EXPORT_FORMATS, ", "))
+	}
+
+	_config.Notify = NotifyConfig{}
+	flag.StringVar(&_config.Notify.WebhookUrl, "notify-webhook-url", os.Getenv(ENV_NOTIFY_WEBHOOK_URL), "(Optional) URL to POST sync lifecycle events to as JSON")
+	flag.StringVar(&_config.Notify.SnsTopicArn, "notify-sns-topic-arn", os.Getenv(ENV_NOTIFY_SNS_TOPIC_ARN), "(Optional) AWS SNS topic ARN to publish sync lifecycle events to")
+
+	var notifyEvents string
+	flag.StringVar(&notifyEvents, "notify-events", os.Getenv(ENV_NOTIFY_EVENTS), "Comma-separated sync lifecycle events to notify on: start, success, failure, table_failed, table_synced (default: '"+DEFAULT_NOTIFY_EVENTS+"')")
+	if notifyEvents == "" {
+		notifyEvents = DEFAULT_NOTIFY_EVENTS
+	}
+	for _, event := range strings.Split(notifyEvents, ",") {
+		if !slices.Contains(NOTIFY_EVENTS, event) {
+			panic("Invalid notify event " + event + ". Must be one of " + strings.Join(NOTIFY_EVENTS, ", "))
+		}
+		_config.Notify.Events = append(_config.Notify.Events, event)
+	}
+
+	if _config.Notify.SnsTopicArn != "" {
+		flag.StringVar(&_config.Notify.Aws.Region, "notify-aws-region", os.Getenv(ENV_NOTIFY_AWS_REGION), "(Optional) AWS region for publishing to --notify-sns-topic-arn. When no --notify-aws-* option is set and --storage-type=S3, the AWS storage region and credentials are used")
+		flag.StringVar(&_config.Notify.Aws.AccessKeyId, "notify-aws-access-key-id", os.Getenv(ENV_NOTIFY_AWS_ACCESS_KEY_ID), "(Optional) AWS access key ID for publishing to --notify-sns-topic-arn")
+		flag.StringVar(&_config.Notify.Aws.SecretAccessKey, "notify-aws-secret-access-key", os.Getenv(ENV_NOTIFY_AWS_SECRET_ACCESS_KEY), "(Optional) AWS secret access key for publishing to --notify-sns-topic-arn")
+		flag.StringVar(&_config.Notify.Aws.Profile, "notify-aws-profile", os.Getenv(ENV_NOTIFY_AWS_PROFILE), "(Optional) Named AWS profile for publishing to --notify-sns-topic-arn")
+		flag.StringVar(&_config.Notify.Aws.RoleArn, "notify-aws-role-arn", os.Getenv(ENV_NOTIFY_AWS_ROLE_ARN), "(Optional) IAM role ARN to assume via STS for publishing to --notify-sns-topic-arn")
+	}
+
 	if _config.StorageType == STORAGE_TYPE_S3 {
 		_config.Aws = AwsConfig{}
 
+		flag.StringVar(&_config.Aws.StorageProvider, "storage-provider", os.Getenv(ENV_STORAGE_PROVIDER), "S3-compatible provider: aws, minio, r2, gcs, ibmcos, other (default: '"+DEFAULT_STORAGE_PROVIDER+"')")
+		if _config.Aws.StorageProvider == "" {
+			_config.Aws.StorageProvider = DEFAULT_STORAGE_PROVIDER
+		} else if !slices.Contains(STORAGE_PROVIDERS, _config.Aws.StorageProvider) {
+			panic("Invalid storage provider " + _config.Aws.StorageProvider + ". Must be one of " + strings.Join(STORAGE_PROVIDERS, ", "))
+		}
+		providerDefaults := storageProviderDefaults[_config.Aws.StorageProvider]
+
 		flag.StringVar(&_config.Aws.Region, "aws-region", os.Getenv(ENV_AWS_REGION), "AWS region")
-		if _config.Aws.Region == "" {
+		if _config.Aws.Region == "" && _config.Aws.StorageProvider == STORAGE_PROVIDER_AWS {
 			panic("AWS region is required")
 		}
 
@@ -149,14 +307,62 @@ func registerFlags() {
 		}
 
 		flag.StringVar(&_config.Aws.AccessKeyId, "aws-access-key-id", os.Getenv(ENV_AWS_ACCESS_KEY_ID), "AWS access key ID")
-		if _config.Aws.AccessKeyId == "" {
-			panic("AWS access key ID is required")
+		flag.StringVar(&_config.Aws.SecretAccessKey, "aws-secret-access-key", os.Getenv(ENV_AWS_SECRET_ACCESS_KEY), "AWS secret access key")
+		flag.StringVar(&_config.Aws.SessionToken, "aws-session-token", os.Getenv(ENV_AWS_SESSION_TOKEN), "(Optional) AWS session token for temporary/STS credentials")
+		flag.StringVar(&_config.Aws.Profile, "aws-profile", os.Getenv(ENV_AWS_PROFILE), "(Optional) Named profile from the shared AWS config/credentials files")
+		flag.StringVar(&_config.Aws.RoleArn, "aws-role-arn", os.Getenv(ENV_AWS_ROLE_ARN), "(Optional) IAM role ARN to assume via STS")
+		flag.StringVar(&_config.Aws.WebIdentityTokenFile, "aws-web-identity-token-file", os.Getenv(ENV_AWS_WEB_IDENTITY_TOKEN_FILE), "(Optional) Path to a web identity token file for OIDC federation")
+
+		flag.StringVar(&_config.Aws.Endpoint, "aws-endpoint", os.Getenv(ENV_AWS_ENDPOINT), "(Optional) S3 endpoint URL override, e.g. for MinIO/R2/GCS/IBM COS")
+		if _config.Aws.Endpoint == "" {
+			_config.Aws.Endpoint = providerDefaults.Endpoint
 		}
 
-		flag.StringVar(&_config.Aws.SecretAccessKey, "aws-secret-access-key", os.Getenv(ENV_AWS_SECRET_ACCESS_KEY), "AWS secret access key")
-		if _config.Aws.SecretAccessKey == "" {
-			panic("AWS secret access key is required")
+		var forcePathStyle string
+		flag.StringVar(&forcePathStyle, "aws-s3-force-path-style", os.Getenv(ENV_AWS_S3_FORCE_PATH_STYLE), "(Optional) Use path-style S3 addressing instead of virtual-hosted-style")
+		if forcePathStyle != "" {
+			_config.Aws.S3ForcePathStyle = forcePathStyle == "true"
+		} else {
+			_config.Aws.S3ForcePathStyle = providerDefaults.S3ForcePathStyle
 		}
+
+		if _config.Aws.AccessKeyId == "" && _config.Aws.RoleArn == "" && _config.Aws.WebIdentityTokenFile == "" && _config.Aws.Profile == "" {
+			panic("One of AWS access key ID, profile, role ARN, or web identity token file is required")
+		}
+	}
+
+	if _config.StorageType == STORAGE_TYPE_AZURE {
+		_config.Azure = AzureConfig{}
+
+		flag.StringVar(&_config.Azure.ConnectionString, "azure-storage-connection-string", os.Getenv(ENV_AZURE_STORAGE_CONNECTION_STRING), "(Optional) Azure Storage connection string. Takes precedence over account name/key")
+
+		flag.StringVar(&_config.Azure.Container, "azure-storage-container", os.Getenv(ENV_AZURE_STORAGE_CONTAINER), "Azure Storage container name")
+		if _config.Azure.Container == "" {
+			panic("Azure Storage container name is required")
+		}
+
+		if _config.Azure.ConnectionString == "" {
+			flag.StringVar(&_config.Azure.AccountName, "azure-storage-account", os.Getenv(ENV_AZURE_STORAGE_ACCOUNT), "Azure Storage account name")
+			if _config.Azure.AccountName == "" {
+				panic("Azure Storage account name is required")
+			}
+
+			flag.StringVar(&_config.Azure.AccountKey, "azure-storage-key", os.Getenv(ENV_AZURE_STORAGE_KEY), "Azure Storage account key")
+			flag.StringVar(&_config.Azure.SasToken, "azure-storage-sas-token", os.Getenv(ENV_AZURE_STORAGE_SAS_TOKEN), "(Optional) Azure Storage SAS token, used instead of an account key")
+			if _config.Azure.AccountKey == "" && _config.Azure.SasToken == "" {
+				panic("Azure Storage account key or SAS token is required")
+			}
+
+			flag.StringVar(&_config.Azure.Endpoint, "azure-storage-endpoint", os.Getenv(ENV_AZURE_STORAGE_ENDPOINT), "(Optional) Azure Storage account URL (default: 'https://<account>.blob.core.windows.net')")
+		}
+	}
+
+	// SNS publishing needs its own AWS credentials: --storage-type may be
+	// LOCAL or AZURE, in which case _config.Aws is never populated. Only
+	// fall back to the S3 storage credentials when the user hasn't supplied
+	// anything notify-specific, so an explicit --notify-aws-* always wins.
+	if _config.Notify.SnsTopicArn != "" && _config.Notify.Aws == (AwsConfig{}) && _config.StorageType == STORAGE_TYPE_S3 {
+		_config.Notify.Aws = _config.Aws
 	}
 }
 
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+)
+
+const (
+	NOTIFY_EVENT_START        = "start"
+	NOTIFY_EVENT_SUCCESS      = "success"
+	NOTIFY_EVENT_FAILURE      = "failure"
+	NOTIFY_EVENT_TABLE_FAILED = "table_failed"
+	NOTIFY_EVENT_TABLE_SYNCED = "table_synced"
+)
+
+var NOTIFY_EVENTS = []string{
+	NOTIFY_EVENT_START, NOTIFY_EVENT_SUCCESS, NOTIFY_EVENT_FAILURE,
+	NOTIFY_EVENT_TABLE_FAILED, NOTIFY_EVENT_TABLE_SYNCED,
+}
+
+// SyncEvent is the stable JSON payload delivered to every configured
+// Notifier. Fields that don't apply to a given event (e.g. Schema/Table for
+// a "start" event) are left at their zero value and omitted.
+type SyncEvent struct {
+	Event     string    `json:"event"`
+	Timestamp time.Time `json:"timestamp"`
+	Database  string    `json:"database"`
+	Schema    string    `json:"schema,omitempty"`
+	Table     string    `json:"table,omitempty"`
+	Rows      int64     `json:"rows,omitempty"`
+	Duration  string    `json:"duration,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Stack     string    `json:"stack,omitempty"`
+}
+
+// Notifier delivers sync lifecycle events to an external system. Failures to
+// notify are logged, not panicked on — a dead webhook must never fail a
+// sync.
+type Notifier interface {
+	Notify(event SyncEvent)
+}
+
+// NewNotifiers builds one Notifier per configured destination (webhook
+// and/or SNS), each already filtering for the configured event set.
+func NewNotifiers(config *Config) []Notifier {
+	var notifiers []Notifier
+
+	if config.Notify.WebhookUrl != "" {
+		notifiers = append(notifiers, &eventFilteringNotifier{
+			events:   config.Notify.Events,
+			notifier: NewWebhookNotifier(config),
+		})
+	}
+
+	if config.Notify.SnsTopicArn != "" {
+		notifiers = append(notifiers, &eventFilteringNotifier{
+			events:   config.Notify.Events,
+			notifier: NewSnsNotifier(config),
+		})
+	}
+
+	return notifiers
+}
+
+type eventFilteringNotifier struct {
+	events   []string
+	notifier Notifier
+}
+
+func (filtering *eventFilteringNotifier) Notify(event SyncEvent) {
+	for _, allowedEvent := range filtering.events {
+		if allowedEvent == event.Event {
+			filtering.notifier.Notify(event)
+			return
+		}
+	}
+}
+
+type WebhookNotifier struct {
+	config *Config
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(config *Config) *WebhookNotifier {
+	return &WebhookNotifier{config: config, url: config.Notify.WebhookUrl, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (notifier *WebhookNotifier) Notify(event SyncEvent) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		LogError(notifier.config, "Failed to marshal notify event:", err)
+		return
+	}
+
+	resp, err := notifier.client.Post(notifier.url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		LogError(notifier.config, "Failed to POST notify event to webhook:", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		LogError(notifier.config, "Webhook notify returned status", resp.StatusCode)
+	}
+}
+
+type SnsNotifier struct {
+	config   *Config
+	topicArn string
+	client   *sns.Client
+}
+
+func NewSnsNotifier(config *Config) *SnsNotifier {
+	awsCfg := buildAwsSession(config.Notify.Aws)
+	return &SnsNotifier{config: config, topicArn: config.Notify.SnsTopicArn, client: sns.NewFromConfig(awsCfg)}
+}
+
+func (notifier *SnsNotifier) Notify(event SyncEvent) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		LogError(notifier.config, "Failed to marshal notify event:", err)
+		return
+	}
+
+	message := string(payload)
+	_, err = notifier.client.Publish(context.Background(), &sns.PublishInput{
+		TopicArn: &notifier.topicArn,
+		Message:  &message,
+	})
+	if err != nil {
+		LogError(notifier.config, "Failed to publish notify event to SNS:", err)
+	}
+}
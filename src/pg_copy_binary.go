@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+var pgCopyBinarySignature = []byte("PGCOPY\n\377\r\n\000")
+
+// pgCopyBinaryReader decodes the wire format produced by
+// "COPY ... TO STDOUT WITH (FORMAT binary)": an 11-byte signature, a 4-byte
+// flags field, a length-prefixed header extension (always empty in practice),
+// then one tuple per row until a trailing field-count of -1 marks the end.
+// See the Postgres docs for "COPY Binary Format".
+type pgCopyBinaryReader struct {
+	reader *bufio.Reader
+}
+
+func newPgCopyBinaryReader(r io.Reader) (*pgCopyBinaryReader, error) {
+	reader := bufio.NewReader(r)
+
+	signature := make([]byte, len(pgCopyBinarySignature))
+	if _, err := io.ReadFull(reader, signature); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(signature, pgCopyBinarySignature) {
+		return nil, errors.New("unexpected COPY binary signature")
+	}
+
+	var flags int32
+	if err := binary.Read(reader, binary.BigEndian, &flags); err != nil {
+		return nil, err
+	}
+
+	var headerExtensionLength int32
+	if err := binary.Read(reader, binary.BigEndian, &headerExtensionLength); err != nil {
+		return nil, err
+	}
+	if headerExtensionLength > 0 {
+		if _, err := io.CopyN(io.Discard, reader, int64(headerExtensionLength)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &pgCopyBinaryReader{reader: reader}, nil
+}
+
+// ReadTuple returns the raw bytes of each field in the next row (nil for a
+// SQL NULL), or io.EOF once the trailing field-count-of-minus-one marker is
+// reached.
+func (r *pgCopyBinaryReader) ReadTuple() ([][]byte, error) {
+	var fieldCount int16
+	if err := binary.Read(r.reader, binary.BigEndian, &fieldCount); err != nil {
+		return nil, err
+	}
+	if fieldCount == -1 {
+		return nil, io.EOF
+	}
+
+	fields := make([][]byte, fieldCount)
+	for i := range fields {
+		var fieldLength int32
+		if err := binary.Read(r.reader, binary.BigEndian, &fieldLength); err != nil {
+			return nil, err
+		}
+		if fieldLength == -1 {
+			continue
+		}
+
+		data := make([]byte, fieldLength)
+		if _, err := io.ReadFull(r.reader, data); err != nil {
+			return nil, err
+		}
+		fields[i] = data
+	}
+
+	return fields, nil
+}
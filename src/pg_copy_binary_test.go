@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+func buildPgCopyBinaryStream(t *testing.T, tuples [][][]byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	buf.Write(pgCopyBinarySignature)
+	binary.Write(&buf, binary.BigEndian, int32(0)) // flags
+	binary.Write(&buf, binary.BigEndian, int32(0)) // header extension length
+
+	for _, tuple := range tuples {
+		binary.Write(&buf, binary.BigEndian, int16(len(tuple)))
+		for _, field := range tuple {
+			if field == nil {
+				binary.Write(&buf, binary.BigEndian, int32(-1))
+				continue
+			}
+			binary.Write(&buf, binary.BigEndian, int32(len(field)))
+			buf.Write(field)
+		}
+	}
+
+	binary.Write(&buf, binary.BigEndian, int16(-1)) // trailer
+
+	return buf.Bytes()
+}
+
+func TestPgCopyBinaryReaderDecodesTuplesAndNulls(t *testing.T) {
+	stream := buildPgCopyBinaryStream(t, [][][]byte{
+		{[]byte("hello"), nil},
+		{[]byte("world"), []byte{0x01}},
+	})
+
+	reader, err := newPgCopyBinaryReader(bytes.NewReader(stream))
+	if err != nil {
+		t.Fatalf("unexpected error constructing reader: %v", err)
+	}
+
+	first, err := reader.ReadTuple()
+	if err != nil {
+		t.Fatalf("unexpected error reading first tuple: %v", err)
+	}
+	if string(first[0]) != "hello" || first[1] != nil {
+		t.Fatalf("unexpected first tuple: %+v", first)
+	}
+
+	second, err := reader.ReadTuple()
+	if err != nil {
+		t.Fatalf("unexpected error reading second tuple: %v", err)
+	}
+	if string(second[0]) != "world" || !bytes.Equal(second[1], []byte{0x01}) {
+		t.Fatalf("unexpected second tuple: %+v", second)
+	}
+
+	if _, err := reader.ReadTuple(); err != io.EOF {
+		t.Fatalf("expected io.EOF at the trailer, got %v", err)
+	}
+}
+
+func TestPgCopyBinaryReaderRejectsBadSignature(t *testing.T) {
+	_, err := newPgCopyBinaryReader(bytes.NewReader([]byte("not a copy stream at all!!!")))
+	if err == nil {
+		t.Fatal("expected an error for a malformed signature")
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// pgExporter is the extract stage of a table sync: it reads a Postgres
+// table via COPY and streams it into the Iceberg writer, returning the
+// number of rows written.
+type pgExporter interface {
+	Export(syncer *Syncer, conn *pgx.Conn, workerId int, pgSchemaTable SchemaTable) (rowCount int64)
+}
+
+// pgCsvExporter is the original extract path: COPY ... WITH CSV HEADER,
+// re-parsed against pgTableSchemaColumns downstream by the Iceberg writer.
+// Used when --pg-export-format=csv; pgBinaryExporter never falls back to it.
+type pgCsvExporter struct{}
+
+func (exporter *pgCsvExporter) Export(syncer *Syncer, conn *pgx.Conn, workerId int, pgSchemaTable SchemaTable) (rowCount int64) {
+	csvFile, err := syncer.exportPgTableToCsv(conn, workerId, pgSchemaTable)
+	PanicIfError(err)
+	defer csvFile.Close()
+
+	csvReader := csv.NewReader(csvFile)
+	csvHeader, err := csvReader.Read()
+	PanicIfError(err)
+
+	pgSchemaColumns := syncer.pgTableSchemaColumns(conn, pgSchemaTable, csvHeader)
+	reachedEnd := false
+
+	syncer.icebergWriter.Write(pgSchemaTable, pgSchemaColumns, func() [][]string {
+		if reachedEnd {
+			return [][]string{}
+		}
+
+		var rows [][]string
+		for {
+			row, err := csvReader.Read()
+			if err != nil {
+				reachedEnd = true
+				break
+			}
+
+			rows = append(rows, row)
+			if len(rows) >= BATCH_SIZE {
+				break
+			}
+		}
+		rowCount += int64(len(rows))
+		return rows
+	})
+
+	return rowCount
+}
+
+// pgBinaryExporter reads the table via COPY ... WITH (FORMAT binary), the
+// same COPY protocol pgCsvExporter uses but without the string round-trip
+// CSV pays for jsonb, numeric, timestamptz, bytea, arrays, and uuid: each
+// field is decoded straight from its wire format with pgx's built-in codecs
+// (pgtype.Map under the hood), streaming already-typed rows ([]any) into the
+// Iceberg writer.
+type pgBinaryExporter struct{}
+
+func (exporter *pgBinaryExporter) Export(syncer *Syncer, conn *pgx.Conn, workerId int, pgSchemaTable SchemaTable) (rowCount int64) {
+	pgSchemaColumns := syncer.pgTableSchemaColumnsOrdered(conn, pgSchemaTable)
+
+	typeMap := conn.TypeMap()
+	columnOids := make([]uint32, len(pgSchemaColumns))
+	for i, column := range pgSchemaColumns {
+		pgType, ok := typeMap.TypeForName(column.UdtName)
+		if !ok {
+			pgType, _ = typeMap.TypeForName("text")
+		}
+		columnOids[i] = pgType.OID
+	}
+
+	ctx := context.Background()
+	pipeReader, pipeWriter := io.Pipe()
+
+	go func() {
+		quotedTable := pgx.Identifier{pgSchemaTable.Schema, pgSchemaTable.Table}.Sanitize()
+		_, err := conn.PgConn().CopyTo(ctx, pipeWriter, "COPY "+quotedTable+" TO STDOUT WITH (FORMAT binary)")
+		pipeWriter.CloseWithError(err)
+	}()
+
+	copyReader, err := newPgCopyBinaryReader(pipeReader)
+	PanicIfError(err)
+
+	reachedEnd := false
+
+	syncer.icebergWriter.WriteTyped(pgSchemaTable, pgSchemaColumns, func() [][]any {
+		if reachedEnd {
+			return [][]any{}
+		}
+
+		var rows [][]any
+		for {
+			fields, err := copyReader.ReadTuple()
+			if err == io.EOF {
+				reachedEnd = true
+				break
+			}
+			PanicIfError(err)
+
+			row := make([]any, len(fields))
+			for i, field := range fields {
+				if field == nil {
+					continue
+				}
+
+				value, err := typeMap.DecodeValue(columnOids[i], pgtype.BinaryFormatCode, field)
+				PanicIfError(err)
+				row[i] = value
+			}
+			rows = append(rows, row)
+
+			if len(rows) >= BATCH_SIZE {
+				break
+			}
+		}
+
+		rowCount += int64(len(rows))
+		return rows
+	})
+
+	return rowCount
+}
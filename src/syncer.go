@@ -2,9 +2,14 @@ package main
 
 import (
 	"context"
-	"encoding/csv"
+	"errors"
+	"fmt"
 	"os"
+	"runtime/debug"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 )
@@ -14,9 +19,21 @@ const (
 )
 
 type Syncer struct {
-	config        *Config
-	icebergWriter *IcebergWriter
-	icebergReader *IcebergReader
+	config         *Config
+	icebergWriter  *IcebergWriter
+	icebergReader  *IcebergReader
+	relations      map[uint32]SchemaTable // relation OID -> table, tracked from replication Relation messages
+	icebergMutexes sync.Map               // SchemaTable.String() -> *sync.Mutex, guards IcebergWriter calls per table
+	notifiers      []Notifier
+}
+
+// icebergMutexFor returns the mutex guarding IcebergWriter calls for a single
+// table. Scoping the lock per table (instead of one mutex shared by every
+// worker) lets --sync-concurrency workers actually write different tables in
+// parallel; only workers racing on the same table ever block each other.
+func (syncer *Syncer) icebergMutexFor(pgSchemaTable SchemaTable) *sync.Mutex {
+	mutex, _ := syncer.icebergMutexes.LoadOrStore(pgSchemaTable.String(), &sync.Mutex{})
+	return mutex.(*sync.Mutex)
 }
 
 func NewSyncer(config *Config) *Syncer {
@@ -26,10 +43,51 @@ func NewSyncer(config *Config) *Syncer {
 
 	icebergWriter := NewIcebergWriter(config)
 	icebergReader := NewIcebergReader(config)
-	return &Syncer{config: config, icebergWriter: icebergWriter, icebergReader: icebergReader}
+	return &Syncer{
+		config:        config,
+		icebergWriter: icebergWriter,
+		icebergReader: icebergReader,
+		relations:     make(map[uint32]SchemaTable),
+		notifiers:     NewNotifiers(config),
+	}
+}
+
+func (syncer *Syncer) notify(event SyncEvent) {
+	event.Timestamp = time.Now()
+	event.Database = syncer.config.Database
+	for _, notifier := range syncer.notifiers {
+		notifier.Notify(event)
+	}
 }
 
 func (syncer *Syncer) SyncFromPostgres() {
+	syncer.notify(SyncEvent{Event: NOTIFY_EVENT_START})
+
+	defer func() {
+		if r := recover(); r != nil {
+			syncer.notify(SyncEvent{Event: NOTIFY_EVENT_FAILURE, Error: toErrorMessage(r), Stack: string(debug.Stack())})
+			panic(r)
+		}
+	}()
+
+	if syncer.config.Pg.SyncMode != PG_SYNC_MODE_SNAPSHOT {
+		syncer.syncIncrementallyFromPostgres()
+	} else {
+		syncer.snapshotSyncFromPostgres("")
+	}
+
+	syncer.notify(SyncEvent{Event: NOTIFY_EVENT_SUCCESS})
+}
+
+// snapshotSyncFromPostgres performs a full COPY-based sync of every matching
+// table, fanning work out across a bounded pool of workers that each hold
+// their own connection pinned to the same snapshot so every worker sees the
+// same consistent point-in-time. If snapshotName is empty, a fresh snapshot
+// is exported from this transaction; incremental bootstrap instead passes
+// the snapshot already exported alongside the replication slot, so the
+// initial sync and the slot's start position agree on exactly the same
+// consistent point.
+func (syncer *Syncer) snapshotSyncFromPostgres(snapshotName string) {
 	ctx := context.Background()
 
 	onlyTablesMap := make(map[string]bool)
@@ -47,6 +105,13 @@ func (syncer *Syncer) SyncFromPostgres() {
 	_, err = conn.Exec(ctx, "BEGIN TRANSACTION ISOLATION LEVEL SERIALIZABLE READ ONLY DEFERRABLE")
 	PanicIfError(err)
 
+	if snapshotName == "" {
+		snapshotName = syncer.exportPgSnapshot(conn)
+	} else {
+		_, err = conn.Exec(ctx, "SET TRANSACTION SNAPSHOT '"+snapshotName+"'")
+		PanicIfError(err)
+	}
+
 	pgSchemaTables := []SchemaTable{}
 	for _, schema := range syncer.listPgSchemas(conn) {
 		for _, pgSchemaTable := range syncer.listPgSchemaTables(conn, schema) {
@@ -58,13 +123,132 @@ func (syncer *Syncer) SyncFromPostgres() {
 			}
 			if syncTable {
 				pgSchemaTables = append(pgSchemaTables, pgSchemaTable)
-				syncer.syncFromPgTable(conn, pgSchemaTable)
 			}
 		}
 	}
+
+	err = syncer.syncPgTablesConcurrently(ctx, snapshotName, pgSchemaTables)
+	if err != nil {
+		// A bad table must not abort the whole run: each failed table has
+		// already been reported via a table_failed notification (worker
+		// connection/setup errors are only logged here). Reconciling
+		// deleted tables against a partial sync would be wrong, though, so
+		// skip it until a run completes clean.
+		LogError(syncer.config, "Sync completed with per-table error(s):", err)
+		return
+	}
+
 	syncer.deleteOldIcebergSchemaTables(pgSchemaTables)
 }
 
+// syncPgTablesConcurrently pushes pgSchemaTables onto a channel consumed by
+// config.SyncConcurrency workers, each with its own connection set to the
+// coordinator's exported snapshot. Per-table failures are collected rather
+// than aborting the run, so one bad table doesn't block the rest. tableChan
+// is buffered to hold every table up front so the producer below never
+// blocks on a send, even if every worker fails its own setup and none are
+// left to drain it.
+func (syncer *Syncer) syncPgTablesConcurrently(ctx context.Context, snapshotName string, pgSchemaTables []SchemaTable) error {
+	tableChan := make(chan SchemaTable, len(pgSchemaTables))
+	errChan := make(chan error, len(pgSchemaTables))
+
+	var wg sync.WaitGroup
+	workerCount := syncer.config.SyncConcurrency
+	if workerCount > len(pgSchemaTables) {
+		workerCount = len(pgSchemaTables)
+	}
+	if workerCount < 1 {
+		workerCount = 1
+	}
+
+	for i := 0; i < workerCount; i++ {
+		wg.Add(1)
+		go func(workerId int) {
+			defer wg.Done()
+			syncer.syncPgTablesWorker(ctx, workerId, snapshotName, tableChan, errChan)
+		}(i)
+	}
+
+	for _, pgSchemaTable := range pgSchemaTables {
+		tableChan <- pgSchemaTable
+	}
+	close(tableChan)
+	wg.Wait()
+	close(errChan)
+
+	var errs []error
+	for err := range errChan {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (syncer *Syncer) syncPgTablesWorker(ctx context.Context, workerId int, snapshotName string, tableChan <-chan SchemaTable, errChan chan<- error) {
+	conn, err := pgx.Connect(ctx, syncer.config.Pg.DatabaseUrl)
+	if err != nil {
+		errChan <- err
+		return
+	}
+	defer conn.Close(ctx)
+
+	_, err = conn.Exec(ctx, "BEGIN TRANSACTION ISOLATION LEVEL SERIALIZABLE READ ONLY DEFERRABLE")
+	if err != nil {
+		errChan <- err
+		return
+	}
+
+	_, err = conn.Exec(ctx, "SET TRANSACTION SNAPSHOT '"+snapshotName+"'")
+	if err != nil {
+		errChan <- err
+		return
+	}
+
+	for pgSchemaTable := range tableChan {
+		if err := syncer.syncFromPgTableSafely(conn, workerId, pgSchemaTable); err != nil {
+			errChan <- err
+		}
+	}
+}
+
+// syncFromPgTableSafely recovers from the panics that syncFromPgTable and
+// its helpers raise via PanicIfError, turning a single bad table into an
+// error instead of aborting every other worker.
+func (syncer *Syncer) syncFromPgTableSafely(conn *pgx.Conn, workerId int, pgSchemaTable SchemaTable) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			errMessage := toErrorMessage(r)
+			syncer.notify(SyncEvent{
+				Event:  NOTIFY_EVENT_TABLE_FAILED,
+				Schema: pgSchemaTable.Schema,
+				Table:  pgSchemaTable.Table,
+				Error:  errMessage,
+			})
+			err = errors.New("syncing " + pgSchemaTable.String() + ": " + errMessage)
+		}
+	}()
+
+	syncer.syncFromPgTable(conn, workerId, pgSchemaTable)
+	return nil
+}
+
+func toErrorMessage(r any) string {
+	if err, ok := r.(error); ok {
+		return err.Error()
+	}
+	return strings.TrimSpace(fmt.Sprint(r))
+}
+
+// exportPgSnapshot exports the transaction's snapshot so it can be handed
+// off to worker connections (via SET TRANSACTION SNAPSHOT) that must see
+// exactly the same data as the coordinator.
+func (syncer *Syncer) exportPgSnapshot(conn *pgx.Conn) (snapshotName string) {
+	err := conn.QueryRow(context.Background(), "SELECT pg_export_snapshot()").Scan(&snapshotName)
+	PanicIfError(err)
+
+	LogDebug(syncer.config, "Exported Postgres snapshot", snapshotName)
+	return snapshotName
+}
+
 func (syncer *Syncer) listPgSchemas(conn *pgx.Conn) []string {
 	var schemas []string
 
@@ -113,43 +297,91 @@ func (syncer *Syncer) listPgSchemaTables(conn *pgx.Conn, schema string) []Schema
 	return pgSchemaTables
 }
 
-func (syncer *Syncer) syncFromPgTable(conn *pgx.Conn, pgSchemaTable SchemaTable) {
+func (syncer *Syncer) syncFromPgTable(conn *pgx.Conn, workerId int, pgSchemaTable SchemaTable) {
 	LogInfo(syncer.config, "Syncing "+pgSchemaTable.String()+"...")
+	startedAt := time.Now()
+
+	var rowCount int64
+	// IcebergWriter's catalog/metadata mutation isn't safe for concurrent
+	// callers writing the same table, so only one worker at a time may write
+	// a given table's data; workers on different tables don't contend.
+	func() {
+		mutex := syncer.icebergMutexFor(pgSchemaTable)
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		exporter := syncer.pgExporterFor(syncer.config.Pg.ExportFormat)
+		rowCount = exporter.Export(syncer, conn, workerId, pgSchemaTable)
+	}()
+
+	syncer.notify(SyncEvent{
+		Event:    NOTIFY_EVENT_TABLE_SYNCED,
+		Schema:   pgSchemaTable.Schema,
+		Table:    pgSchemaTable.Table,
+		Rows:     rowCount,
+		Duration: time.Since(startedAt).String(),
+	})
+}
 
-	csvFile, err := syncer.exportPgTableToCsv(conn, pgSchemaTable)
-	PanicIfError(err)
-	defer csvFile.Close()
-
-	csvReader := csv.NewReader(csvFile)
-	csvHeader, err := csvReader.Read()
-	PanicIfError(err)
+// pgExporterFor picks the extract-stage implementation for the configured
+// --pg-export-format: "binary" selects pgBinaryExporter, any other value
+// selects pgCsvExporter.
+func (syncer *Syncer) pgExporterFor(format string) pgExporter {
+	if format == PG_EXPORT_FORMAT_BINARY {
+		return &pgBinaryExporter{}
+	}
+	return &pgCsvExporter{}
+}
 
-	pgSchemaColumns := syncer.pgTableSchemaColumns(conn, pgSchemaTable, csvHeader)
-	reachedEnd := false
+func (syncer *Syncer) pgTableSchemaColumns(conn *pgx.Conn, pgSchemaTable SchemaTable, csvHeader []string) []PgSchemaColumn {
+	var pgSchemaColumns []PgSchemaColumn
 
-	syncer.icebergWriter.Write(pgSchemaTable, pgSchemaColumns, func() [][]string {
-		if reachedEnd {
-			return [][]string{}
-		}
+	rows, err := conn.Query(
+		context.Background(),
+		`SELECT
+			column_name,
+			data_type,
+			udt_name,
+			is_nullable,
+			ordinal_position,
+			COALESCE(character_maximum_length, 0),
+			COALESCE(numeric_precision, 0),
+			COALESCE(numeric_scale, 0),
+			COALESCE(datetime_precision, 0)
+		FROM information_schema.columns
+		WHERE table_schema = $1 AND table_name = $2
+		ORDER BY array_position($3, column_name)`,
+		pgSchemaTable.Schema,
+		pgSchemaTable.Table,
+		csvHeader,
+	)
+	PanicIfError(err)
+	defer rows.Close()
 
-		var rows [][]string
-		for {
-			row, err := csvReader.Read()
-			if err != nil {
-				reachedEnd = true
-				break
-			}
+	for rows.Next() {
+		var pgSchemaColumn PgSchemaColumn
+		err = rows.Scan(
+			&pgSchemaColumn.ColumnName,
+			&pgSchemaColumn.DataType,
+			&pgSchemaColumn.UdtName,
+			&pgSchemaColumn.IsNullable,
+			&pgSchemaColumn.OrdinalPosition,
+			&pgSchemaColumn.CharacterMaximumLength,
+			&pgSchemaColumn.NumericPrecision,
+			&pgSchemaColumn.NumericScale,
+			&pgSchemaColumn.DatetimePrecision,
+		)
+		PanicIfError(err)
+		pgSchemaColumns = append(pgSchemaColumns, pgSchemaColumn)
+	}
 
-			rows = append(rows, row)
-			if len(rows) >= BATCH_SIZE {
-				break
-			}
-		}
-		return rows
-	})
+	return pgSchemaColumns
 }
 
-func (syncer *Syncer) pgTableSchemaColumns(conn *pgx.Conn, pgSchemaTable SchemaTable, csvHeader []string) []PgSchemaColumn {
+// pgTableSchemaColumnsOrdered is like pgTableSchemaColumns but for exporters
+// that don't extract through a CSV header, ordering directly by
+// ordinal_position instead of matching against one.
+func (syncer *Syncer) pgTableSchemaColumnsOrdered(conn *pgx.Conn, pgSchemaTable SchemaTable) []PgSchemaColumn {
 	var pgSchemaColumns []PgSchemaColumn
 
 	rows, err := conn.Query(
@@ -166,10 +398,9 @@ func (syncer *Syncer) pgTableSchemaColumns(conn *pgx.Conn, pgSchemaTable SchemaT
 			COALESCE(datetime_precision, 0)
 		FROM information_schema.columns
 		WHERE table_schema = $1 AND table_name = $2
-		ORDER BY array_position($3, column_name)`,
+		ORDER BY ordinal_position`,
 		pgSchemaTable.Schema,
 		pgSchemaTable.Table,
-		csvHeader,
 	)
 	PanicIfError(err)
 	defer rows.Close()
@@ -194,8 +425,8 @@ func (syncer *Syncer) pgTableSchemaColumns(conn *pgx.Conn, pgSchemaTable SchemaT
 	return pgSchemaColumns
 }
 
-func (syncer *Syncer) exportPgTableToCsv(conn *pgx.Conn, pgSchemaTable SchemaTable) (csvFile *os.File, err error) {
-	tempFile, err := CreateTemporaryFile(pgSchemaTable.String())
+func (syncer *Syncer) exportPgTableToCsv(conn *pgx.Conn, workerId int, pgSchemaTable SchemaTable) (csvFile *os.File, err error) {
+	tempFile, err := CreateTemporaryFile(pgSchemaTable.String() + "-worker" + strconv.Itoa(workerId))
 	PanicIfError(err)
 	defer DeleteTemporaryFile(tempFile)
 
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/jackc/pglogrepl"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgproto3"
+)
+
+const (
+	REPLICATION_SLOT_PREFIX = "bemidb_"
+	REPLICATION_PLUGIN      = "pgoutput"
+	REPLICATION_PUBLICATION = "bemidb_publication"
+	SYNC_STATE_FILENAME     = "sync_state.json"
+)
+
+// SyncState is the durable checkpoint for incremental/CDC sync: the last LSN
+// the Iceberg tables are caught up to, so a restarted sync resumes the
+// replication slot instead of re-running a full snapshot.
+type SyncState struct {
+	LastLsn string `json:"last_lsn"`
+}
+
+// syncIncrementallyFromPostgres bootstraps the replication slot on first run
+// (no persisted state yet), otherwise resumes streaming changes from the
+// last confirmed LSN.
+func (syncer *Syncer) syncIncrementallyFromPostgres() {
+	state, err := syncer.loadSyncState()
+	PanicIfError(err)
+
+	if state == nil {
+		syncer.bootstrapIncrementalSync()
+		return
+	}
+
+	syncer.streamReplicationChanges(state)
+}
+
+func (syncer *Syncer) replicationSlotName() string {
+	return REPLICATION_SLOT_PREFIX + syncer.config.Database
+}
+
+func (syncer *Syncer) syncStateFilepath() string {
+	return filepath.Join(syncer.config.StoragePath, SYNC_STATE_FILENAME)
+}
+
+func (syncer *Syncer) loadSyncState() (*SyncState, error) {
+	bytes, err := os.ReadFile(syncer.syncStateFilepath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var state SyncState
+	if err := json.Unmarshal(bytes, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (syncer *Syncer) persistSyncState(state *SyncState) {
+	bytes, err := json.Marshal(state)
+	PanicIfError(err)
+
+	err = os.WriteFile(syncer.syncStateFilepath(), bytes, 0644)
+	PanicIfError(err)
+}
+
+// bootstrapIncrementalSync creates the logical replication slot first,
+// capturing the consistent point and exported snapshot name the slot itself
+// reports, then runs the initial full sync against that exact snapshot.
+// This guarantees the slot's start LSN and the snapshot the full sync reads
+// agree on the same point, so no change is duplicated or missed in the
+// handoff between the snapshot and the first streamed change.
+func (syncer *Syncer) bootstrapIncrementalSync() {
+	snapshotName, consistentPoint := syncer.createReplicationSlot()
+
+	syncer.snapshotSyncFromPostgres(snapshotName)
+
+	syncer.persistSyncState(&SyncState{LastLsn: consistentPoint})
+}
+
+// createReplicationSlot creates the slot with SnapshotAction "export", which
+// makes Postgres export a brand new snapshot taken at the same consistent
+// point the slot starts decoding from. Both values are returned so callers
+// can hand the snapshot to the bootstrap sync and the consistent point to
+// the replication stream's start LSN.
+func (syncer *Syncer) createReplicationSlot() (snapshotName string, consistentPoint string) {
+	ctx := context.Background()
+	replConn, err := pgconn.Connect(ctx, syncer.config.Pg.DatabaseUrl+"?replication=database")
+	PanicIfError(err)
+	defer replConn.Close(ctx)
+
+	result, err := pglogrepl.CreateReplicationSlot(ctx, replConn, syncer.replicationSlotName(), REPLICATION_PLUGIN,
+		pglogrepl.CreateReplicationSlotOptions{Temporary: false, SnapshotAction: "export"})
+	PanicIfError(err)
+
+	LogInfo(syncer.config, "Created replication slot", syncer.replicationSlotName(), "at", result.ConsistentPoint)
+	return result.SnapshotName, result.ConsistentPoint
+}
+
+// streamReplicationChanges connects to the named logical replication slot
+// starting at the last confirmed LSN, decodes pgoutput change messages, and
+// flushes buffered inserts/updates/deletes per table into the Iceberg
+// writer as equality-delete + append batches (merge-on-read).
+func (syncer *Syncer) streamReplicationChanges(state *SyncState) {
+	ctx := context.Background()
+
+	replConn, err := pgconn.Connect(ctx, syncer.config.Pg.DatabaseUrl+"?replication=database")
+	PanicIfError(err)
+	defer replConn.Close(ctx)
+
+	startLsn, err := pglogrepl.ParseLSN(state.LastLsn)
+	PanicIfError(err)
+
+	err = pglogrepl.StartReplication(ctx, replConn, syncer.replicationSlotName(), startLsn, pglogrepl.StartReplicationOptions{
+		PluginArgs: []string{
+			"proto_version '1'",
+			"publication_names '" + REPLICATION_PUBLICATION + "'",
+		},
+	})
+	PanicIfError(err)
+
+	buffers := newChangeBufferSet()
+	lastLsn := startLsn
+
+	for {
+		msg, err := replConn.ReceiveMessage(ctx)
+		PanicIfError(err)
+
+		switch message := msg.(type) {
+		case *pgproto3.CopyData:
+			switch message.Data[0] {
+			case pglogrepl.XLogDataByteID:
+				xld, err := pglogrepl.ParseXLogData(message.Data[1:])
+				PanicIfError(err)
+
+				done := syncer.applyReplicationMessage(buffers, xld.WALData)
+				lastLsn = xld.WALStart + pglogrepl.LSN(len(xld.WALData))
+				if done {
+					syncer.flushChangeBuffers(buffers)
+					syncer.persistSyncState(&SyncState{LastLsn: lastLsn.String()})
+					buffers = newChangeBufferSet()
+					syncer.acknowledgeLsn(ctx, replConn, lastLsn)
+					continue
+				}
+
+			case pglogrepl.PrimaryKeepaliveMessageByteID:
+				syncer.acknowledgeLsn(ctx, replConn, lastLsn)
+			}
+		}
+	}
+}
+
+func (syncer *Syncer) acknowledgeLsn(ctx context.Context, replConn *pgconn.PgConn, lsn pglogrepl.LSN) {
+	err := pglogrepl.SendStandbyStatusUpdate(ctx, replConn, pglogrepl.StandbyStatusUpdate{WALWritePosition: lsn})
+	PanicIfError(err)
+}
+
+// changeBuffer accumulates decoded row changes for a single table between
+// flushes, keyed by SchemaTable so AppendChanges can write one set of
+// equality-delete + data files per table per batch.
+type changeBuffer struct {
+	columns []PgSchemaColumn
+	inserts [][]any
+	updates [][]any
+	deletes [][]any
+}
+
+type changeBufferSet map[string]*changeBuffer
+
+func newChangeBufferSet() changeBufferSet {
+	return changeBufferSet{}
+}
+
+func (buffers changeBufferSet) forTable(pgSchemaTable SchemaTable) *changeBuffer {
+	key := pgSchemaTable.String()
+	buffer, ok := buffers[key]
+	if !ok {
+		buffer = &changeBuffer{}
+		buffers[key] = buffer
+	}
+	return buffer
+}
+
+// applyReplicationMessage decodes a single pgoutput WAL message and buffers
+// the resulting row change. It returns true once a COMMIT message closes out
+// the current transaction, signaling the caller to flush.
+func (syncer *Syncer) applyReplicationMessage(buffers changeBufferSet, walData []byte) (committed bool) {
+	logicalMsg, err := pglogrepl.Parse(walData)
+	PanicIfError(err)
+
+	switch msg := logicalMsg.(type) {
+	case *pglogrepl.RelationMessage:
+		syncer.handleSchemaDrift(buffers, msg)
+
+	case *pglogrepl.InsertMessage:
+		pgSchemaTable := syncer.relationSchemaTable(msg.RelationID)
+		buffer := buffers.forTable(pgSchemaTable)
+		buffer.inserts = append(buffer.inserts, decodeTupleData(msg.Tuple))
+
+	case *pglogrepl.UpdateMessage:
+		pgSchemaTable := syncer.relationSchemaTable(msg.RelationID)
+		buffer := buffers.forTable(pgSchemaTable)
+		buffer.updates = append(buffer.updates, decodeTupleData(msg.NewTuple))
+
+	case *pglogrepl.DeleteMessage:
+		pgSchemaTable := syncer.relationSchemaTable(msg.RelationID)
+		buffer := buffers.forTable(pgSchemaTable)
+		buffer.deletes = append(buffer.deletes, decodeTupleData(msg.OldTuple))
+
+	case *pglogrepl.CommitMessage:
+		committed = true
+	}
+
+	return committed
+}
+
+// handleSchemaDrift records the relation's OID and passes the replicated
+// relation's columns to IcebergWriter.EvolveSchema on every Relation
+// message; no comparison against earlier columns is made here. It also
+// records the columns on the
+// table's changeBuffer so the next flush's AppendChanges call knows the
+// shape of the rows it's writing.
+func (syncer *Syncer) handleSchemaDrift(buffers changeBufferSet, relation *pglogrepl.RelationMessage) {
+	pgSchemaTable := SchemaTable{Schema: relation.Namespace, Table: relation.RelationName}
+	syncer.relations[relation.RelationID] = pgSchemaTable
+
+	pgSchemaColumns := make([]PgSchemaColumn, len(relation.Columns))
+	for i, column := range relation.Columns {
+		pgSchemaColumns[i] = PgSchemaColumn{ColumnName: column.Name, OrdinalPosition: i + 1}
+	}
+
+	syncer.icebergWriter.EvolveSchema(pgSchemaTable, pgSchemaColumns)
+	buffers.forTable(pgSchemaTable).columns = pgSchemaColumns
+}
+
+func (syncer *Syncer) relationSchemaTable(relationId uint32) SchemaTable {
+	pgSchemaTable, ok := syncer.relations[relationId]
+	if !ok {
+		panic("Received a replication message for an unknown relation")
+	}
+	return pgSchemaTable
+}
+
+func decodeTupleData(tuple *pglogrepl.TupleData) []any {
+	if tuple == nil {
+		return nil
+	}
+
+	values := make([]any, len(tuple.Columns))
+	for i, column := range tuple.Columns {
+		switch column.DataType {
+		case 'n', 'u': // NULL and unchanged TOAST both carry no data
+			values[i] = nil
+		default:
+			values[i] = string(column.Data)
+		}
+	}
+	return values
+}
+
+func (syncer *Syncer) flushChangeBuffers(buffers changeBufferSet) {
+	for key, buffer := range buffers {
+		if len(buffer.inserts) == 0 && len(buffer.updates) == 0 && len(buffer.deletes) == 0 {
+			continue
+		}
+
+		pgSchemaTable := syncer.relations[syncer.relationIdForKey(key)]
+		LogInfo(syncer.config, "Applying", len(buffer.inserts), "insert(s),", len(buffer.updates), "update(s),",
+			len(buffer.deletes), "delete(s) to", pgSchemaTable.String())
+
+		syncer.icebergWriter.AppendChanges(pgSchemaTable, buffer.columns, buffer.inserts, buffer.updates, buffer.deletes)
+	}
+}
+
+func (syncer *Syncer) relationIdForKey(key string) uint32 {
+	for relationId, pgSchemaTable := range syncer.relations {
+		if pgSchemaTable.String() == key {
+			return relationId
+		}
+	}
+	panic("Unknown relation for buffered changes " + key)
+}